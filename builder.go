@@ -0,0 +1,88 @@
+package robotstxt
+
+// Builder builds a RobotsData fluently, as a symmetrical counterpart to
+// parsing one with FromString.
+type Builder struct {
+	order  []string
+	agents map[string]*UserAgent
+
+	sitemaps []string
+	current  string
+}
+
+// NewBuilder creates an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		agents: make(map[string]*UserAgent),
+	}
+}
+
+// Agent selects the user agent that subsequent Allow, Disallow, and
+// CrawlDelay calls apply to, creating it if it doesn't exist yet. Calling
+// Agent again with a name already used (case-insensitively) resumes that
+// same block, merging further rules into it.
+func (b *Builder) Agent(name string) *Builder {
+	token := canonicalUserAgent(name)
+
+	if _, exists := b.agents[token]; !exists {
+		b.agents[token] = &UserAgent{Name: name}
+		b.order = append(b.order, token)
+	}
+
+	b.current = token
+
+	return b
+}
+
+// Allow adds an Allow rule to the current user agent.
+func (b *Builder) Allow(path string) *Builder {
+	return b.addRule(Rule{Allow: true, Path: path})
+}
+
+// Disallow adds a Disallow rule to the current user agent.
+func (b *Builder) Disallow(path string) *Builder {
+	return b.addRule(Rule{Allow: false, Path: path})
+}
+
+func (b *Builder) addRule(rule Rule) *Builder {
+	if b.current == "" {
+		return b
+	}
+
+	ua := b.agents[b.current]
+	ua.Rules = append(ua.Rules, rule)
+
+	return b
+}
+
+// CrawlDelay sets the crawl delay, in seconds, for the current user agent.
+func (b *Builder) CrawlDelay(seconds int) *Builder {
+	if b.current == "" {
+		return b
+	}
+
+	delay := seconds
+	b.agents[b.current].CrawlDelay = &delay
+
+	return b
+}
+
+// Sitemap adds a sitemap URL.
+func (b *Builder) Sitemap(url string) *Builder {
+	b.sitemaps = append(b.sitemaps, url)
+	return b
+}
+
+// Build assembles the configured user agents and sitemaps into a RobotsData.
+func (b *Builder) Build() *RobotsData {
+	rd := &RobotsData{
+		Sitemaps:   append([]string(nil), b.sitemaps...),
+		UserAgents: make(map[string]UserAgent, len(b.order)),
+	}
+
+	for _, token := range b.order {
+		rd.UserAgents[token] = *b.agents[token]
+	}
+
+	return rd
+}
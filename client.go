@@ -0,0 +1,215 @@
+package robotstxt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// defaultTTL is how long a successfully fetched robots.txt is cached
+	// before the Client re-fetches it.
+	defaultTTL = 24 * time.Hour
+	// defaultUnavailableFor is how long a host whose robots.txt returned a
+	// 5xx or 429 response is treated as "disallow all", per RFC 9309 §2.5.
+	defaultUnavailableFor = 5 * time.Minute
+	// defaultMaxBodySize is the number of bytes read from a robots.txt
+	// response; RFC 9309 §2.5 requires parsers to process at least the
+	// first 500 kibibytes.
+	defaultMaxBodySize = 500 * 1024
+	// maxRedirects is how many redirects a robots.txt fetch will follow
+	// before giving up.
+	maxRedirects = 5
+	// defaultMaxHosts bounds the Client's per-host cache so that a
+	// long-running process fetching robots.txt from many distinct hosts
+	// over its lifetime can't accumulate unbounded entries, the same risk
+	// patternCache guards against for compiled patterns.
+	defaultMaxHosts = 4096
+)
+
+// Client fetches robots.txt files over HTTP and caches the parsed result per
+// host, applying the RFC 9309 §2.3.1 status-code rules.
+type Client struct {
+	transport      http.RoundTripper
+	ttl            time.Duration
+	unavailableFor time.Duration
+	maxBodySize    int64
+
+	cache *lruCache[*cacheEntry]
+}
+
+// cacheEntry holds the cached outcome for a host: either a parsed
+// RobotsData, or a blanket allow/disallow decided by the HTTP status code.
+type cacheEntry struct {
+	data        *RobotsData
+	allowAll    bool
+	disallowAll bool
+	expiresAt   time.Time
+}
+
+// Option configures a Client built with NewClient.
+type Option func(*Client)
+
+// WithTTL sets how long a successfully fetched robots.txt is cached.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.ttl = ttl
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to fetch robots.txt files.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithUnavailableFor sets how long a host is treated as "disallow all" after
+// its robots.txt responded with a 5xx or 429 status, before it is retried.
+func WithUnavailableFor(d time.Duration) Option {
+	return func(c *Client) {
+		c.unavailableFor = d
+	}
+}
+
+// WithMaxBodySize sets the maximum number of bytes read from a robots.txt
+// response body.
+func WithMaxBodySize(n int64) Option {
+	return func(c *Client) {
+		c.maxBodySize = n
+	}
+}
+
+// WithMaxHosts sets how many distinct hosts' robots.txt outcomes the Client
+// caches at once. Once the limit is reached, the least-recently-used host is
+// evicted to make room for the new one.
+func WithMaxHosts(n int) Option {
+	return func(c *Client) {
+		c.cache = newLRUCache[*cacheEntry](n)
+	}
+}
+
+// NewClient creates a Client with the given options applied over sane
+// defaults: a 24h TTL, http.DefaultTransport, a 5 minute unavailability
+// window, a 500 KiB max body size, and caching up to 4096 distinct hosts.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		transport:      http.DefaultTransport,
+		ttl:            defaultTTL,
+		unavailableFor: defaultUnavailableFor,
+		maxBodySize:    defaultMaxBodySize,
+		cache:          newLRUCache[*cacheEntry](defaultMaxHosts),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Allowed reports whether URL may be crawled by userAgent, fetching and
+// caching URL's host's robots.txt as needed.
+func (c *Client) Allowed(ctx context.Context, userAgent string, URL string) (bool, error) {
+	u, err := url.Parse(URL)
+	if err != nil {
+		return false, err
+	}
+
+	entry := c.entryFor(ctx, u)
+
+	switch {
+	case entry.allowAll:
+		return true, nil
+	case entry.disallowAll:
+		return false, nil
+	default:
+		return entry.data.IsAllowed(userAgent, u.RequestURI()), nil
+	}
+}
+
+// entryFor returns the cached entry for u's host, fetching a fresh one if
+// there is none or it has expired.
+func (c *Client) entryFor(ctx context.Context, u *url.URL) *cacheEntry {
+	entry, ok := c.cache.get(u.Host)
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry
+	}
+
+	entry = c.fetch(ctx, u)
+	c.cache.add(u.Host, entry)
+
+	return entry
+}
+
+// fetch retrieves and parses u's host's robots.txt, applying the RFC 9309
+// §2.3.1 status-code rules. It never returns an error: an unreachable host
+// is treated the same as a 5xx response.
+func (c *Client) fetch(ctx context.Context, u *url.URL) *cacheEntry {
+	robotsURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+
+	resp, err := c.fetchFollowingRedirects(ctx, robotsURL.String(), 0)
+	if err != nil {
+		return c.unavailableEntry()
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		body := io.NopCloser(io.LimitReader(resp.Body, c.maxBodySize))
+		data, parseErr := FromResponse(&http.Response{StatusCode: resp.StatusCode, Body: body})
+		if parseErr != nil {
+			return &cacheEntry{allowAll: true, expiresAt: time.Now().Add(c.ttl)}
+		}
+
+		return &cacheEntry{data: data, expiresAt: time.Now().Add(c.ttl)}
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return c.unavailableEntry()
+	default:
+		// 4xx other than 429: no robots.txt means no restrictions.
+		return &cacheEntry{allowAll: true, expiresAt: time.Now().Add(c.ttl)}
+	}
+}
+
+func (c *Client) unavailableEntry() *cacheEntry {
+	return &cacheEntry{disallowAll: true, expiresAt: time.Now().Add(c.unavailableFor)}
+}
+
+// fetchFollowingRedirects issues a GET request through the Client's
+// transport, following up to maxRedirects redirects.
+func (c *Client) fetchFollowingRedirects(ctx context.Context, rawURL string, redirects int) (*http.Response, error) {
+	if redirects > maxRedirects {
+		return nil, fmt.Errorf("robotstxt: too many redirects fetching %s", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return resp, nil
+	}
+
+	location := resp.Header.Get("Location")
+	resp.Body.Close()
+
+	if location == "" {
+		return nil, fmt.Errorf("robotstxt: redirect response from %s has no Location header", rawURL)
+	}
+
+	next, err := req.URL.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetchFollowingRedirects(ctx, next.String(), redirects+1)
+}
@@ -0,0 +1,183 @@
+package robotstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newResponse(status int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestClient_Allowed_ParsesOnSuccess(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "/robots.txt", req.URL.Path)
+		return newResponse(http.StatusOK, "User-agent: *\nDisallow: /admin\n", nil), nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	allowed, err := c.Allowed(context.Background(), "*", "https://example.com/admin")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	allowed, err = c.Allowed(context.Background(), "*", "https://example.com/blog")
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestClient_Allowed_MatchesAgainstQueryString(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusOK, "User-agent: *\nDisallow: /search?\n", nil), nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	allowed, err := c.Allowed(context.Background(), "*", "https://example.com/search?q=x")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	allowed, err = c.Allowed(context.Background(), "*", "https://example.com/search")
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestClient_Allowed_EvictsLeastRecentlyUsedHostPastMaxHosts(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return newResponse(http.StatusOK, "User-agent: *\nDisallow: /admin\n", nil), nil
+	})
+
+	c := NewClient(WithTransport(transport), WithMaxHosts(1))
+
+	_, err := c.Allowed(context.Background(), "*", "https://a.example.com/admin")
+	require.NoError(t, err)
+
+	// Caching a second host evicts "a.example.com", since the cache only
+	// holds 1 host.
+	_, err = c.Allowed(context.Background(), "*", "https://b.example.com/admin")
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+
+	// "a.example.com" is no longer cached, so this refetches.
+	_, err = c.Allowed(context.Background(), "*", "https://a.example.com/admin")
+	require.NoError(t, err)
+	require.Equal(t, 3, requests)
+}
+
+func TestClient_Allowed_ClientErrorAllowsAll(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusNotFound, "", nil), nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	allowed, err := c.Allowed(context.Background(), "*", "https://example.com/admin")
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestClient_Allowed_ServerErrorDisallowsAll(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return newResponse(http.StatusInternalServerError, "", nil), nil
+	})
+
+	c := NewClient(WithTransport(transport), WithUnavailableFor(time.Minute))
+
+	allowed, err := c.Allowed(context.Background(), "*", "https://example.com/blog")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	// Cached: a second call within the unavailability window must not refetch.
+	allowed, err = c.Allowed(context.Background(), "*", "https://example.com/blog")
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Equal(t, 1, requests)
+}
+
+func TestClient_Allowed_TooManyRequestsDisallowsAll(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusTooManyRequests, "", nil), nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	allowed, err := c.Allowed(context.Background(), "*", "https://example.com/blog")
+	require.NoError(t, err)
+	require.False(t, allowed)
+}
+
+func TestClient_Allowed_FollowsRedirects(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if req.URL.Host == "example.com" {
+			header := make(http.Header)
+			header.Set("Location", "https://mirror.example.com/robots.txt")
+			return newResponse(http.StatusMovedPermanently, "", header), nil
+		}
+
+		return newResponse(http.StatusOK, "User-agent: *\nDisallow: /admin\n", nil), nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	allowed, err := c.Allowed(context.Background(), "*", "https://example.com/admin")
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Equal(t, 2, requests)
+}
+
+func TestClient_Allowed_TooManyRedirectsDisallowsAll(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		header := make(http.Header)
+		header.Set("Location", "https://example.com/robots.txt")
+		return newResponse(http.StatusFound, "", header), nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	// An endless redirect loop is treated as unreachable, i.e. disallow all.
+	allowed, err := c.Allowed(context.Background(), "*", "https://example.com/admin")
+	require.NoError(t, err)
+	require.False(t, allowed)
+}
@@ -2,10 +2,13 @@ package robotstxt
 
 import (
 	"bufio"
+	"container/list"
 	"errors"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	_ "golang.org/x/lint"
 )
@@ -14,6 +17,14 @@ import (
 type RobotsData struct {
 	Sitemaps   []string
 	UserAgents map[string]UserAgent
+
+	// Host is the Yandex-style Host directive, if one was present.
+	Host string
+
+	// Extensions holds directive values that have no registered handler
+	// (see RegisterDirective), keyed by the lowercased directive name, so
+	// unrecognized directives round-trip instead of being discarded.
+	Extensions map[string][]string
 }
 
 // Rule represents a rule in robots.txt file.
@@ -27,6 +38,10 @@ type UserAgent struct {
 	Name       string
 	CrawlDelay *int
 	Rules      []Rule
+
+	// CleanParams holds the Yandex-style Clean-param values declared for
+	// this user agent.
+	CleanParams []string
 }
 
 type robotsRuleKey string
@@ -48,6 +63,71 @@ var rulesKeysSlice = []robotsRuleKey{
 	sitemapRuleKey,
 }
 
+// DirectiveHandler processes the value of a custom robots.txt directive.
+// currentUA is the canonical (lowercased) user agent the directive applies
+// to, or "" if the directive appeared outside any user-agent group.
+type DirectiveHandler func(rd *RobotsData, currentUA string, value string) error
+
+// directiveRegistryMu guards directiveRegistry, since RegisterDirective may
+// be called concurrently with an in-flight FromString/FromResponse.
+var directiveRegistryMu sync.RWMutex
+
+// directiveRegistry maps a lowercased directive name to the handler that
+// processes it. Host and Clean-param are registered by default.
+var directiveRegistry = map[string]DirectiveHandler{
+	"host":        hostDirectiveHandler,
+	"clean-param": cleanParamDirectiveHandler,
+}
+
+// RegisterDirective registers handler for the named directive (matched
+// case-insensitively), so callers can add support for vendor-specific
+// robots.txt extensions beyond the built-in Host and Clean-param. Directives
+// without a registered handler are preserved verbatim on RobotsData.Extensions.
+func RegisterDirective(name string, handler DirectiveHandler) {
+	directiveRegistryMu.Lock()
+	defer directiveRegistryMu.Unlock()
+
+	directiveRegistry[strings.ToLower(name)] = handler
+}
+
+// lookupDirective returns the handler registered for key, if any.
+func lookupDirective(key string) (DirectiveHandler, bool) {
+	directiveRegistryMu.RLock()
+	defer directiveRegistryMu.RUnlock()
+
+	handler, registered := directiveRegistry[key]
+
+	return handler, registered
+}
+
+// hostDirectiveHandler implements the Yandex Host directive: the first
+// occurrence wins.
+func hostDirectiveHandler(rd *RobotsData, _ string, value string) error {
+	if rd.Host == "" {
+		rd.Host = value
+	}
+
+	return nil
+}
+
+// cleanParamDirectiveHandler implements the Yandex Clean-param directive,
+// storing each value on the user agent it was declared under.
+func cleanParamDirectiveHandler(rd *RobotsData, currentUA string, value string) error {
+	if currentUA == "" {
+		return nil
+	}
+
+	ua, exists := rd.UserAgents[currentUA]
+	if !exists {
+		return nil
+	}
+
+	ua.CleanParams = append(ua.CleanParams, value)
+	rd.UserAgents[currentUA] = ua
+
+	return nil
+}
+
 var (
 	// ErrorNoSuchUserAgent is returned when there is no such user agent in UserAgents.
 	ErrorNoSuchUserAgent = errors.New("no such user agent")
@@ -88,9 +168,10 @@ func FromBytes(bytes []byte) (*RobotsData, error) {
 	return FromString(string(bytes))
 }
 
-// GetUserAgent returns rules for particular UserAgent.
+// GetUserAgent returns rules for particular UserAgent. Matching is
+// case-insensitive: "googlebot" finds a group declared as "Googlebot".
 func (rb *RobotsData) GetUserAgent(userAgent string) (*UserAgent, error) {
-	ua, ok := rb.UserAgents[userAgent]
+	ua, ok := rb.UserAgents[canonicalUserAgent(userAgent)]
 	if !ok {
 		return nil, ErrorNoSuchUserAgent
 	}
@@ -99,7 +180,7 @@ func (rb *RobotsData) GetUserAgent(userAgent string) (*UserAgent, error) {
 
 // GetCrawlDelay returns crawl delay for particular UserAgent.
 func (rb *RobotsData) GetCrawlDelay(userAgent string) (*int, error) {
-	ua, ok := rb.UserAgents[userAgent]
+	ua, ok := rb.UserAgents[canonicalUserAgent(userAgent)]
 	if !ok {
 		return nil, ErrorNoSuchUserAgent
 	}
@@ -111,24 +192,185 @@ func (rb *RobotsData) GetCrawlDelay(userAgent string) (*int, error) {
 	return ua.CrawlDelay, nil
 }
 
+// canonicalUserAgent normalizes a product token the way RFC 9309 §2.2.1
+// compares them: case-insensitively.
+func canonicalUserAgent(userAgent string) string {
+	return strings.ToLower(strings.TrimSpace(userAgent))
+}
+
 // IsAllowed checks if the URL is allowed for the user agent.
 func (rb *RobotsData) IsAllowed(userAgent string, URL string) bool {
+	rule, matched := rb.Match(userAgent, URL)
+	if !matched {
+		return true
+	}
+
+	return rule.Allow
+}
+
+// Match returns the rule that decides whether URL is allowed for userAgent,
+// along with whether any rule matched at all. Per Google's and RFC 9309's
+// evaluation rules, the most specific (longest) matching pattern wins; an
+// Allow rule wins a tie against a Disallow rule of the same specificity.
+func (rb *RobotsData) Match(userAgent string, URL string) (rule Rule, matched bool) {
 	applicableRules := rb.getApplicableRules(userAgent)
 
-	// Check the rules from most specific to the least specific
-	for _, rule := range applicableRules {
-		if strings.HasPrefix(URL, rule.Path) {
-			return rule.Allow
+	bestSpecificity := -1
+
+	for _, r := range applicableRules {
+		if !MatchPath(r.Path, URL) {
+			continue
+		}
+
+		specificity := pathSpecificity(r.Path)
+		if !matched || specificity > bestSpecificity || (specificity == bestSpecificity && r.Allow && !rule.Allow) {
+			rule = r
+			bestSpecificity = specificity
+			matched = true
 		}
 	}
 
-	return true
+	return rule, matched
+}
+
+// pathSpecificity scores a rule path by its literal character count, with
+// "*" contributing zero, so the most specific (longest literal) pattern can
+// be picked among several matches.
+func pathSpecificity(path string) int {
+	return len(path) - strings.Count(path, "*")
+}
+
+// MatchPath reports whether url matches the robots.txt pattern.
+//
+// Patterns follow Google's robots.txt spec: "*" matches any sequence of
+// characters (including none) and a trailing "$" anchors the match to the
+// end of url. Without a trailing "$", the pattern only needs to match a
+// prefix of url.
+func MatchPath(pattern, url string) bool {
+	return compilePattern(pattern).MatchString(url)
+}
+
+// patternCacheLimit bounds patternCache so that a long-running process (e.g.
+// one using a Client, fetching robots.txt from many distinct hosts over its
+// lifetime) can't accumulate an unbounded number of compiled patterns. Once
+// the limit is reached, the least-recently-used pattern is evicted to make
+// room for the new one.
+const patternCacheLimit = 4096
+
+// patternCache holds pattern matchers keyed by their source pattern so that
+// each distinct rule path is compiled into a regular expression only once,
+// keeping per-URL matching at O(len(url)), up to patternCacheLimit distinct
+// patterns.
+var patternCache = newLRUCache[*regexp.Regexp](patternCacheLimit)
+
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patternCache.get(pattern); ok {
+		return re
+	}
+
+	re := regexp.MustCompile(buildPatternRegexp(pattern))
+	patternCache.add(pattern, re)
+
+	return re
+}
+
+// lruCache is a fixed-capacity, least-recently-used cache. It exists so
+// callers like patternCache and Client's per-host cache can't grow without
+// bound; unlike sync.Map, entries are evicted once the cache is full.
+type lruCache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
 }
 
-// getApplicableRules retrieves rules for a specific user-agent.
+type lruEntry[V any] struct {
+	key   string
+	value V
+}
+
+func newLRUCache[V any](capacity int) *lruCache[V] {
+	return &lruCache[V]{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, capacity),
+	}
+}
+
+func (c *lruCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(*lruEntry[V]).value, true
+}
+
+func (c *lruCache[V]) add(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		elem.Value.(*lruEntry[V]).value = value
+
+		return
+	}
+
+	elem := c.order.PushFront(&lruEntry[V]{key: key, value: value})
+	c.entries[key] = elem
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*lruEntry[V]).key)
+		}
+	}
+}
+
+// buildPatternRegexp translates a robots.txt path pattern into an anchored
+// regular expression, treating "*" as a wildcard and a trailing "$" as an
+// end-of-string anchor.
+func buildPatternRegexp(pattern string) string {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+
+	segments := strings.Split(pattern, "*")
+	for i, segment := range segments {
+		segments[i] = regexp.QuoteMeta(segment)
+	}
+
+	expr := "^" + strings.Join(segments, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	return expr
+}
+
+// getApplicableRules retrieves rules for a specific user-agent, following
+// RFC 9309 §2.2.1: the group whose token is the longest case-insensitive
+// prefix of the request's user agent wins, falling back to "*" only if no
+// group's token is a prefix of it.
 func (rb *RobotsData) getApplicableRules(userAgent string) []Rule {
+	token := canonicalUserAgent(userAgent)
+
 	// Exact match
-	if u, exists := rb.UserAgents[userAgent]; exists {
+	if u, exists := rb.UserAgents[token]; exists {
+		return u.Rules
+	}
+
+	// Longest matching product token, e.g. "Googlebot-News" falls back to "Googlebot"
+	if u, exists := rb.longestPrefixUserAgent(token); exists {
 		return u.Rules
 	}
 
@@ -140,54 +382,112 @@ func (rb *RobotsData) getApplicableRules(userAgent string) []Rule {
 	return []Rule{}
 }
 
+// longestPrefixUserAgent finds the user-agent group whose canonical token is
+// the longest prefix of token.
+func (rb *RobotsData) longestPrefixUserAgent(token string) (UserAgent, bool) {
+	var best UserAgent
+	bestLen := -1
+
+	for key, ua := range rb.UserAgents {
+		if key == "*" || key == "" {
+			continue
+		}
+
+		if strings.HasPrefix(token, key) && len(key) > bestLen {
+			best = ua
+			bestLen = len(key)
+		}
+	}
+
+	return best, bestLen >= 0
+}
+
+// parseRules scans a robots.txt file into per-user-agent rules and crawl
+// delays. Per RFC 9309 §2.1, consecutive User-agent lines with no
+// intervening rule form a single group: every rule that follows applies to
+// each user agent named in that group, until a User-agent line following a
+// rule starts a new group.
 func (rb *RobotsData) parseRules(scanner *bufio.Scanner) error {
 	rb.UserAgents = make(map[string]UserAgent)
 
-	var currentUserAgent string
 	rules := make(map[string][]Rule)
 	delays := make(map[string]*int)
+	names := make(map[string]string)
+	var extensions []pendingDirective
+
+	var group []string
+	groupOpen := false
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-		rule, val := parseLine(line)
+		rule, key, val := parseLine(line)
+
+		if rule == unknownRuleKey && key != "" {
+			extensions = append(extensions, pendingDirective{
+				key:   key,
+				group: append([]string(nil), group...),
+				value: val,
+			})
+		}
+
 		if rule == userAgentRuleKey {
-			currentUserAgent = val
-			if _, exists := rules[currentUserAgent]; !exists {
-				rules[currentUserAgent] = []Rule{}
+			if !groupOpen {
+				group = nil
+				groupOpen = true
 			}
 
-			delays[currentUserAgent] = nil
+			token := canonicalUserAgent(val)
+			group = append(group, token)
+
+			if _, exists := rules[token]; !exists {
+				rules[token] = []Rule{}
+			}
+
+			if _, exists := names[token]; !exists {
+				names[token] = val
+			}
+
+			delays[token] = nil
 		}
 
 		if rule == allowRuleKey {
-			if currentUserAgent == "" {
+			if len(group) == 0 {
 				return ErrorMissingUserAgent
 			}
 
-			rules[currentUserAgent] = append(rules[currentUserAgent], Rule{
-				Allow: true,
-				Path:  val,
-			})
+			groupOpen = false
+			for _, token := range group {
+				rules[token] = append(rules[token], Rule{
+					Allow: true,
+					Path:  val,
+				})
+			}
 		}
 
 		if rule == disallowRuleKey {
-			if currentUserAgent == "" {
+			if len(group) == 0 {
 				return ErrorMissingUserAgent
 			}
 
-			rules[currentUserAgent] = append(rules[currentUserAgent], Rule{
-				Allow: false,
-				Path:  val,
-			})
+			groupOpen = false
+			for _, token := range group {
+				rules[token] = append(rules[token], Rule{
+					Allow: false,
+					Path:  val,
+				})
+			}
 		}
 
 		if rule == crawlDelayRuleKey {
-			if currentUserAgent == "" {
+			if len(group) == 0 {
 				return ErrorMissingUserAgent
 			}
 
+			groupOpen = false
 			res, _ := strconv.Atoi(val)
-			delays[currentUserAgent] = &res
+			for _, token := range group {
+				delays[token] = &res
+			}
 		}
 
 		if rule == sitemapRuleKey {
@@ -197,12 +497,20 @@ func (rb *RobotsData) parseRules(scanner *bufio.Scanner) error {
 
 	for u, rule := range rules {
 		rb.UserAgents[u] = UserAgent{
-			Name:       u,
+			Name:       names[u],
 			Rules:      rule,
 			CrawlDelay: delays[u],
 		}
 	}
 
+	// Directive handlers run once UserAgents is fully populated, since e.g.
+	// Clean-param attaches its value to an already-resolved UserAgent.
+	for _, ext := range extensions {
+		if err := rb.applyExtensionDirective(ext); err != nil {
+			return err
+		}
+	}
+
 	if err := scanner.Err(); err != nil {
 		return err
 	}
@@ -210,22 +518,67 @@ func (rb *RobotsData) parseRules(scanner *bufio.Scanner) error {
 	return nil
 }
 
-func parseLine(line string) (robotsRuleKey, string) {
+// pendingDirective records a non-core directive line seen while scanning,
+// to be applied once parsing finishes and UserAgents is fully built.
+type pendingDirective struct {
+	key   string
+	group []string
+	value string
+}
+
+// applyExtensionDirective dispatches a pending directive to its registered
+// handler, once per user agent in the group it was declared under (or once
+// with no user agent, if it was declared outside any group). Directives
+// without a registered handler are preserved on rb.Extensions instead.
+func (rb *RobotsData) applyExtensionDirective(ext pendingDirective) error {
+	handler, registered := lookupDirective(ext.key)
+	if !registered {
+		if rb.Extensions == nil {
+			rb.Extensions = make(map[string][]string)
+		}
+
+		rb.Extensions[ext.key] = append(rb.Extensions[ext.key], ext.value)
+
+		return nil
+	}
+
+	if len(ext.group) == 0 {
+		return handler(rb, "", ext.value)
+	}
+
+	for _, token := range ext.group {
+		if err := handler(rb, token, ext.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseLine splits a robots.txt line into its directive and value. key is
+// the lowercased directive name ("" for blank lines, comments, or other
+// lines without a "key: value" shape); rule is the matching core
+// robotsRuleKey, or unknownRuleKey for anything else.
+func parseLine(line string) (rule robotsRuleKey, key string, value string) {
+	if strings.HasPrefix(line, "#") {
+		return unknownRuleKey, "", ""
+	}
+
 	parts := strings.SplitN(line, ":", 2)
 	if len(parts) != 2 {
-		return unknownRuleKey, ""
+		return unknownRuleKey, "", ""
 	}
 
-	key := strings.TrimSpace(parts[0])
-	value := strings.TrimSpace(parts[1])
+	key = strings.ToLower(strings.TrimSpace(parts[0]))
+	value = strings.TrimSpace(parts[1])
 
 	for _, ruleKey := range rulesKeysSlice {
-		if strings.ToLower(key) == ruleKey.toString() {
-			return ruleKey, value
+		if key == ruleKey.toString() {
+			return ruleKey, key, value
 		}
 	}
 
-	return unknownRuleKey, ""
+	return unknownRuleKey, key, value
 }
 
 func (rlk robotsRuleKey) toString() string {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -52,7 +53,7 @@ var robotsDataResult = RobotsData{
 				},
 			},
 		},
-		"Googlebot": {
+		"googlebot": {
 			Name:       "Googlebot",
 			CrawlDelay: &crawlDelay2,
 			Rules: []Rule{
@@ -74,7 +75,7 @@ var robotsDataResult = RobotsData{
 				},
 			},
 		},
-		"Spambot": {
+		"spambot": {
 			Name: "Spambot",
 			Rules: []Rule{
 				{
@@ -122,7 +123,7 @@ var robotsDataExpected = RobotsData{
 				},
 			},
 		},
-		"OtherBot": {
+		"otherbot": {
 			Name: "OtherBot",
 			Rules: []Rule{
 				{
@@ -292,3 +293,347 @@ func TestIsAllowed(t *testing.T) {
 		require.Equal(t, test.isAllow, isAllowed, fmt.Sprintf("case %d, url: %s", i, test.url))
 	}
 }
+
+func TestMatchPath(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		pattern string
+		url     string
+		match   bool
+	}
+
+	tests := []tcase{
+		{"/private*/", "/private/", true},
+		{"/private*/", "/private-area/", true},
+		{"/private*/", "/priv", false},
+
+		{"/*.gif$", "/image.gif", true},
+		{"/*.gif$", "/image.gif?size=large", false},
+		{"/*.gif$", "/image.gifx", false},
+
+		{"/fish", "/fish", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/fish/salmon.html", true},
+		{"/fish", "/desert/fish", false},
+
+		{"/fish*.php", "/fish.php", true},
+		{"/fish*.php", "/fishheads/catfish.php?parameters", true},
+		{"/fish*.php", "/Fish.PHP", false},
+	}
+
+	for i, test := range tests {
+		matched := MatchPath(test.pattern, test.url)
+		require.Equal(t, test.match, matched, fmt.Sprintf("case %d, pattern: %s, url: %s", i, test.pattern, test.url))
+	}
+}
+
+func TestPatternCache_EvictsLeastRecentlyUsedPastCapacity(t *testing.T) {
+	t.Parallel()
+
+	cache := newLRUCache[*regexp.Regexp](2)
+
+	a := compilePattern("/a")
+	cache.add("/a", a)
+	b := compilePattern("/b")
+	cache.add("/b", b)
+
+	// Touch "/a" so "/b" becomes the least recently used entry.
+	_, ok := cache.get("/a")
+	require.True(t, ok)
+
+	c := compilePattern("/c")
+	cache.add("/c", c)
+
+	_, ok = cache.get("/b")
+	require.False(t, ok, "/b should have been evicted")
+
+	_, ok = cache.get("/a")
+	require.True(t, ok, "/a was touched after /b, so it should still be cached")
+
+	_, ok = cache.get("/c")
+	require.True(t, ok)
+}
+
+func TestMatch_LongestAndAllowOverDisallow(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name    string
+		rules   []Rule
+		url     string
+		isAllow bool
+	}
+
+	tests := []tcase{
+		{
+			name: "more specific allow wins over shorter disallow",
+			rules: []Rule{
+				{Allow: false, Path: "/folder"},
+				{Allow: true, Path: "/folder/page"},
+			},
+			url:     "/folder/page",
+			isAllow: true,
+		},
+		{
+			name: "more specific disallow wins over shorter allow",
+			rules: []Rule{
+				{Allow: true, Path: "/folder"},
+				{Allow: false, Path: "/folder/page"},
+			},
+			url:     "/folder/page",
+			isAllow: false,
+		},
+		{
+			name: "equal length tie favors allow",
+			rules: []Rule{
+				{Allow: false, Path: "/page"},
+				{Allow: true, Path: "/page"},
+			},
+			url:     "/page",
+			isAllow: true,
+		},
+		{
+			name: "literal with more characters wins over a shorter wildcard pattern",
+			rules: []Rule{
+				{Allow: false, Path: "/page*"},
+				{Allow: true, Path: "/page1"},
+			},
+			url:     "/page1",
+			isAllow: true,
+		},
+	}
+
+	for _, test := range tests {
+		rd := RobotsData{
+			UserAgents: map[string]UserAgent{
+				"*": {Name: "*", Rules: test.rules},
+			},
+		}
+
+		isAllowed := rd.IsAllowed("*", test.url)
+		require.Equal(t, test.isAllow, isAllowed, test.name)
+	}
+}
+
+func TestMatch_ReturnsDecidingRule(t *testing.T) {
+	t.Parallel()
+
+	rule, matched := robotsDataResult.Match("Googlebot", "/register/u1")
+
+	require.True(t, matched)
+	require.Equal(t, Rule{Allow: true, Path: "/register/u1"}, rule)
+}
+
+func TestMatch_NoRuleMatched(t *testing.T) {
+	t.Parallel()
+
+	rule, matched := robotsDataResult.Match("Googlebot", "/about")
+
+	require.False(t, matched)
+	require.Equal(t, Rule{}, rule)
+}
+
+func TestGetUserAgent_CaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	userAgent, err := robotsDataResult.GetUserAgent("googlebot")
+
+	require.NoError(t, err)
+	require.Equal(t, "Googlebot", userAgent.Name)
+}
+
+func TestIsAllowed_UserAgentTokenMatching(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name      string
+		userAgent string
+		url       string
+		isAllow   bool
+	}
+
+	tests := []tcase{
+		{"longest prefix falls back from a product variant", "Googlebot-News", "/images", false},
+		{"longest prefix falls back from a product variant with mixed case", "googlebot-image", "/register", false},
+		{"mixed case exact match", "GOOGLEBOT", "/images", false},
+		{"mixed case exact match allowed path", "GOOGLEBOT", "/register/u1", true},
+		{"unrelated product token falls back to wildcard", "Bingbot", "/admin", false},
+	}
+
+	for _, test := range tests {
+		isAllowed := robotsDataResult.IsAllowed(test.userAgent, test.url)
+		require.Equal(t, test.isAllow, isAllowed, test.name)
+	}
+}
+
+func TestIsAllowed_MultipleUserAgentsShareABlock(t *testing.T) {
+	t.Parallel()
+
+	rd := RobotsData{
+		UserAgents: map[string]UserAgent{
+			"googlebot": {Name: "Googlebot", Rules: []Rule{{Allow: false, Path: "/private"}}},
+			"bingbot":   {Name: "Bingbot", Rules: []Rule{{Allow: false, Path: "/private"}}},
+		},
+	}
+
+	require.False(t, rd.IsAllowed("Googlebot", "/private"))
+	require.False(t, rd.IsAllowed("BingBot", "/private"))
+	require.True(t, rd.IsAllowed("Googlebot", "/public"))
+}
+
+func TestFromString_GroupsConsecutiveUserAgentLines(t *testing.T) {
+	t.Parallel()
+
+	robots := "User-agent: A\n" +
+		"User-agent: B\n" +
+		"Disallow: /x\n" +
+		"Crawl-delay: 3\n"
+
+	rd, err := FromString(robots)
+	require.NoError(t, err)
+
+	a, err := rd.GetUserAgent("A")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{{Allow: false, Path: "/x"}}, a.Rules)
+	require.Equal(t, 3, *a.CrawlDelay)
+
+	b, err := rd.GetUserAgent("B")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{{Allow: false, Path: "/x"}}, b.Rules)
+	require.Equal(t, 3, *b.CrawlDelay)
+}
+
+func TestFromString_GroupsInterleavedWithComments(t *testing.T) {
+	t.Parallel()
+
+	robots := "# crawlers sharing the same rules\n" +
+		"User-agent: A\n" +
+		"# another one\n" +
+		"User-agent: B\n" +
+		"\n" +
+		"Disallow: /x\n"
+
+	rd, err := FromString(robots)
+	require.NoError(t, err)
+
+	a, err := rd.GetUserAgent("A")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{{Allow: false, Path: "/x"}}, a.Rules)
+
+	b, err := rd.GetUserAgent("B")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{{Allow: false, Path: "/x"}}, b.Rules)
+}
+
+func TestFromString_NewUserAgentAfterRuleStartsNewGroup(t *testing.T) {
+	t.Parallel()
+
+	robots := "User-agent: A\n" +
+		"User-agent: B\n" +
+		"Disallow: /x\n" +
+		"User-agent: C\n" +
+		"Disallow: /y\n"
+
+	rd, err := FromString(robots)
+	require.NoError(t, err)
+
+	a, err := rd.GetUserAgent("A")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{{Allow: false, Path: "/x"}}, a.Rules)
+
+	c, err := rd.GetUserAgent("C")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{{Allow: false, Path: "/y"}}, c.Rules)
+}
+
+func TestFromString_SameUserAgentMergedAcrossGroups(t *testing.T) {
+	t.Parallel()
+
+	robots := "User-agent: A\n" +
+		"Disallow: /x\n" +
+		"\n" +
+		"User-agent: A\n" +
+		"Disallow: /y\n"
+
+	rd, err := FromString(robots)
+	require.NoError(t, err)
+
+	a, err := rd.GetUserAgent("A")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{
+		{Allow: false, Path: "/x"},
+		{Allow: false, Path: "/y"},
+	}, a.Rules)
+}
+
+func TestFromString_HostDirective(t *testing.T) {
+	t.Parallel()
+
+	robots := "User-agent: *\n" +
+		"Disallow: /admin\n" +
+		"Host: www.example.com\n"
+
+	rd, err := FromString(robots)
+	require.NoError(t, err)
+	require.Equal(t, "www.example.com", rd.Host)
+}
+
+func TestFromString_CleanParamDirective(t *testing.T) {
+	t.Parallel()
+
+	robots := "User-agent: Yandex\n" +
+		"Disallow: /admin\n" +
+		"Clean-param: ref /some/path\n" +
+		"Clean-param: sid\n"
+
+	rd, err := FromString(robots)
+	require.NoError(t, err)
+
+	ua, err := rd.GetUserAgent("Yandex")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ref /some/path", "sid"}, ua.CleanParams)
+}
+
+func TestFromString_UnknownDirectivesPreservedAsExtensions(t *testing.T) {
+	t.Parallel()
+
+	robots := "User-agent: *\n" +
+		"Disallow: /admin\n" +
+		"Request-rate: 1/10\n" +
+		"Request-rate: 2/20\n"
+
+	rd, err := FromString(robots)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"request-rate": {"1/10", "2/20"},
+	}, rd.Extensions)
+}
+
+func TestFromString_CommentsContainingColonsAreNotDirectives(t *testing.T) {
+	t.Parallel()
+
+	robots := "User-agent: *\n" +
+		"# Disallow: nothing below applies retroactively\n" +
+		"Disallow: /admin\n"
+
+	rd, err := FromString(robots)
+	require.NoError(t, err)
+	require.Nil(t, rd.Extensions)
+}
+
+func TestRegisterDirective_CustomHandler(t *testing.T) {
+	var seen []string
+	RegisterDirective("x-crawl-priority", func(rd *RobotsData, currentUA string, value string) error {
+		seen = append(seen, currentUA+"="+value)
+		return nil
+	})
+
+	robots := "User-agent: Googlebot\n" +
+		"Disallow: /admin\n" +
+		"X-Crawl-Priority: high\n"
+
+	_, err := FromString(robots)
+	require.NoError(t, err)
+	require.Equal(t, []string{"googlebot=high"}, seen)
+}
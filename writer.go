@@ -0,0 +1,111 @@
+package robotstxt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteTo writes rb as a robots.txt file: user-agent blocks (with their
+// rules, crawl delay, and Clean-param values), then Host, then any unknown
+// Extensions, then Sitemaps. User-agent blocks and Extensions are written in
+// a deterministic order (sorted by key) so the output is stable across
+// calls, and round-trips through FromString back to an equal RobotsData.
+func (rb *RobotsData) WriteTo(w io.Writer) (int64, error) {
+	tokens := make([]string, 0, len(rb.UserAgents))
+	for token := range rb.UserAgents {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	var buf bytes.Buffer
+	wroteBlock := false
+
+	for i, token := range tokens {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		writeUserAgentBlock(&buf, rb.UserAgents[token])
+		wroteBlock = true
+	}
+
+	if rb.Host != "" {
+		if wroteBlock {
+			buf.WriteString("\n")
+		}
+
+		fmt.Fprintf(&buf, "Host: %s\n", rb.Host)
+		wroteBlock = true
+	}
+
+	if len(rb.Extensions) > 0 {
+		if wroteBlock {
+			buf.WriteString("\n")
+		}
+
+		writeExtensions(&buf, rb.Extensions)
+		wroteBlock = true
+	}
+
+	if len(rb.Sitemaps) > 0 {
+		if wroteBlock {
+			buf.WriteString("\n")
+		}
+
+		for _, sitemap := range rb.Sitemaps {
+			fmt.Fprintf(&buf, "Sitemap: %s\n", sitemap)
+		}
+	}
+
+	n, err := w.Write(buf.Bytes())
+
+	return int64(n), err
+}
+
+func writeUserAgentBlock(buf *bytes.Buffer, ua UserAgent) {
+	fmt.Fprintf(buf, "User-agent: %s\n", ua.Name)
+
+	for _, rule := range ua.Rules {
+		key := "Allow"
+		if !rule.Allow {
+			key = "Disallow"
+		}
+
+		fmt.Fprintf(buf, "%s: %s\n", key, rule.Path)
+	}
+
+	if ua.CrawlDelay != nil {
+		fmt.Fprintf(buf, "Crawl-delay: %d\n", *ua.CrawlDelay)
+	}
+
+	for _, cleanParam := range ua.CleanParams {
+		fmt.Fprintf(buf, "Clean-param: %s\n", cleanParam)
+	}
+}
+
+func writeExtensions(buf *bytes.Buffer, extensions map[string][]string) {
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range extensions[key] {
+			fmt.Fprintf(buf, "%s: %s\n", titleDirective(key), value)
+		}
+	}
+}
+
+// titleDirective capitalizes a directive's first letter, matching the
+// convention used by the built-in directives (e.g. "Crawl-delay").
+func titleDirective(key string) string {
+	if key == "" {
+		return key
+	}
+
+	return strings.ToUpper(key[:1]) + key[1:]
+}
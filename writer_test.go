@@ -0,0 +1,146 @@
+package robotstxt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTo_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	original, err := FromString(robotsStr)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	n, err := original.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	roundTripped, err := FromString(buf.String())
+	require.NoError(t, err)
+	require.Equal(t, original, roundTripped)
+}
+
+func TestWriteTo_RoundTripWithHostCleanParamAndExtensions(t *testing.T) {
+	t.Parallel()
+
+	robots := "User-agent: Yandex\n" +
+		"Disallow: /admin\n" +
+		"Clean-param: ref /some/path\n" +
+		"Host: www.example.com\n" +
+		"Request-rate: 1/10\n" +
+		"\n" +
+		"Sitemap: https://www.example.com/sitemap.xml\n"
+
+	original, err := FromString(robots)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = original.WriteTo(&buf)
+	require.NoError(t, err)
+
+	roundTripped, err := FromString(buf.String())
+	require.NoError(t, err)
+	require.Equal(t, original, roundTripped)
+}
+
+func TestWriteTo_GroupsRulesByUserAgent(t *testing.T) {
+	t.Parallel()
+
+	delay := 5
+	rd := RobotsData{
+		Sitemaps: []string{"https://x/sitemap.xml"},
+		UserAgents: map[string]UserAgent{
+			"googlebot": {
+				Name:       "Googlebot",
+				CrawlDelay: &delay,
+				Rules: []Rule{
+					{Allow: false, Path: "/admin"},
+					{Allow: true, Path: "/admin/public"},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	_, err := rd.WriteTo(&buf)
+	require.NoError(t, err)
+
+	require.Equal(t, "User-agent: Googlebot\n"+
+		"Disallow: /admin\n"+
+		"Allow: /admin/public\n"+
+		"Crawl-delay: 5\n"+
+		"\n"+
+		"Sitemap: https://x/sitemap.xml\n", buf.String())
+}
+
+func TestBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	rd := NewBuilder().
+		Agent("Googlebot").
+		Disallow("/admin").
+		Allow("/admin/public").
+		CrawlDelay(5).
+		Sitemap("https://x/sitemap.xml").
+		Build()
+
+	delay := 5
+	require.Equal(t, &RobotsData{
+		Sitemaps: []string{"https://x/sitemap.xml"},
+		UserAgents: map[string]UserAgent{
+			"googlebot": {
+				Name:       "Googlebot",
+				CrawlDelay: &delay,
+				Rules: []Rule{
+					{Allow: false, Path: "/admin"},
+					{Allow: true, Path: "/admin/public"},
+				},
+			},
+		},
+	}, rd)
+}
+
+func TestBuilder_MultipleAgentsAndResumingABlock(t *testing.T) {
+	t.Parallel()
+
+	rd := NewBuilder().
+		Agent("Googlebot").
+		Disallow("/admin").
+		Agent("Bingbot").
+		Disallow("/private").
+		Agent("Googlebot").
+		Allow("/admin/public").
+		Build()
+
+	googlebot, err := rd.GetUserAgent("Googlebot")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{
+		{Allow: false, Path: "/admin"},
+		{Allow: true, Path: "/admin/public"},
+	}, googlebot.Rules)
+
+	bingbot, err := rd.GetUserAgent("Bingbot")
+	require.NoError(t, err)
+	require.Equal(t, []Rule{{Allow: false, Path: "/private"}}, bingbot.Rules)
+}
+
+func TestBuilder_RoundTripsThroughFromString(t *testing.T) {
+	t.Parallel()
+
+	built := NewBuilder().
+		Agent("*").
+		Disallow("/admin").
+		Sitemap("https://x/sitemap.xml").
+		Build()
+
+	var buf bytes.Buffer
+	_, err := built.WriteTo(&buf)
+	require.NoError(t, err)
+
+	parsed, err := FromString(buf.String())
+	require.NoError(t, err)
+	require.Equal(t, built, parsed)
+}